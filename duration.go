@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/atomic/internal/nocmp"
+)
+
+//go:generate go run ./internal/gen-atomicint -name Duration -wrapped time.Duration -store-as int64 -atomic-suffix Int64 -parse time.ParseDuration -file duration.go -ext-file duration_ext.go
+
+// Duration is an atomic wrapper around time.Duration.
+type Duration struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v int64
+}
+
+// NewDuration creates a new Duration.
+func NewDuration(i time.Duration) *Duration {
+	return &Duration{v: int64(i)}
+}
+
+// Load atomically loads the wrapped value.
+func (i *Duration) Load() time.Duration {
+	return time.Duration(atomic.LoadInt64(&i.v))
+}
+
+// Add atomically adds to the wrapped time.Duration and returns the new value.
+func (i *Duration) Add(n time.Duration) time.Duration {
+	return time.Duration(atomic.AddInt64(&i.v, int64(n)))
+}
+
+// Sub atomically subtracts from the wrapped time.Duration and returns the new value.
+func (i *Duration) Sub(n time.Duration) time.Duration {
+	return time.Duration(atomic.AddInt64(&i.v, -int64(n)))
+}
+
+// Inc atomically increments the wrapped time.Duration and returns the new value.
+func (i *Duration) Inc() time.Duration {
+	return i.Add(1)
+}
+
+// Dec atomically decrements the wrapped time.Duration and returns the new value.
+func (i *Duration) Dec() time.Duration {
+	return i.Sub(1)
+}
+
+// CAS is an atomic compare-and-swap.
+func (i *Duration) CAS(old, new time.Duration) bool {
+	return atomic.CompareAndSwapInt64(&i.v, int64(old), int64(new))
+}
+
+// Store atomically stores the passed value.
+func (i *Duration) Store(n time.Duration) {
+	atomic.StoreInt64(&i.v, int64(n))
+}
+
+// Swap atomically swaps the wrapped time.Duration and returns the old value.
+func (i *Duration) Swap(n time.Duration) time.Duration {
+	return time.Duration(atomic.SwapInt64(&i.v, int64(n)))
+}
+
+// String encodes the wrapped value as a string.
+func (i *Duration) String() string {
+	return i.Load().String()
+}
+
+// MarshalJSON encodes the wrapped time.Duration into JSON.
+func (i *Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.Load().String())
+}
+
+// UnmarshalJSON decodes JSON into the wrapped time.Duration.
+func (i *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	i.Store(v)
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	atom := NewDuration(5 * time.Second)
+
+	t.Run("Load", func(t *testing.T) {
+		if got := atom.Load(); got != 5*time.Second {
+			t.Errorf("Load() = %v, want %v", got, 5*time.Second)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		if got, want := atom.String(), (5 * time.Second).String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CAS", func(t *testing.T) {
+		if !atom.CAS(5*time.Second, time.Minute) {
+			t.Error("CAS() with correct old value should succeed")
+		}
+		if got := atom.Load(); got != time.Minute {
+			t.Errorf("Load() = %v, want %v", got, time.Minute)
+		}
+	})
+}
+
+func TestDuration_ConcurrentAdd(t *testing.T) {
+	atom := NewDuration(0)
+
+	const (
+		goroutines = 50
+		perRoutine = 100
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perRoutine; j++ {
+				atom.Add(time.Second)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := time.Duration(goroutines*perRoutine) * time.Second
+	if got := atom.Load(); got != want {
+		t.Errorf("Load() after concurrent Add() = %v, want %v", got, want)
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	tests := []struct {
+		desc  string
+		value time.Duration
+		json  string
+	}{
+		{desc: "zero", value: 0, json: `"0s"`},
+		{desc: "sub-second", value: 250 * time.Millisecond, json: `"250ms"`},
+		{desc: "minutes", value: 3 * time.Minute, json: `"3m0s"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			atom := NewDuration(tt.value)
+
+			bs, err := json.Marshal(atom)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if got := string(bs); got != tt.json {
+				t.Errorf("Marshal() = %s, want %s", got, tt.json)
+			}
+
+			var got Duration
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if got.Load() != tt.value {
+				t.Errorf("Unmarshal() = %v, want %v", got.Load(), tt.value)
+			}
+		})
+	}
+}
@@ -20,17 +20,19 @@
 
 package atomic
 
-// Error is an atomic type-safe wrapper for error values.
-type Error struct{ v Value }
+import (
+	"sync/atomic"
 
-type storedError struct{ Value error }
+	"go.uber.org/atomic/internal/nocmp"
+)
 
-func wrapError(v error) storedError {
-	return storedError{v}
-}
+//go:generate go run ./internal/gen-atomicwrapper -name Error -type error -wrapped storedError -pack wrapError -unpack unwrapError -file error.go -ext-file error_ext.go
+
+// Error is an atomic type-safe wrapper for error values.
+type Error struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
 
-func unwrapError(v storedError) error {
-	return v.Value
+	v atomic.Value
 }
 
 // NewError creates a new Error.
@@ -57,3 +59,18 @@ func (x *Error) Load() error {
 func (x *Error) Store(v error) {
 	x.v.Store(wrapError(v))
 }
+
+// CompareAndSwap is an atomic compare-and-swap for error values.
+func (x *Error) CompareAndSwap(old, new error) (swapped bool) {
+	return x.v.CompareAndSwap(wrapError(old), wrapError(new))
+}
+
+// Swap atomically stores the given error and returns the old
+// error that was previously wrapped, if any existed.
+func (x *Error) Swap(new error) (old error) {
+	v := x.v.Swap(wrapError(new))
+	if v == nil {
+		return nil
+	}
+	return unwrapError(v.(storedError))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestError_Swap(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	atom := NewError(errFirst)
+	if got := atom.Swap(errSecond); got != errFirst {
+		t.Errorf("Swap() returned %v, want %v", got, errFirst)
+	}
+	if got := atom.Load(); got != errSecond {
+		t.Errorf("Load() = %v, want %v", got, errSecond)
+	}
+
+	// Swapping an unset Error returns nil for the old value.
+	if got := NewError(nil).Swap(errFirst); got != nil {
+		t.Errorf("Swap() on unset Error returned %v, want nil", got)
+	}
+}
+
+func TestError_CompareAndSwap(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	atom := NewError(errFirst)
+
+	if !atom.CompareAndSwap(errFirst, errSecond) {
+		t.Error("CompareAndSwap() with correct old value should succeed")
+	}
+	if got := atom.Load(); got != errSecond {
+		t.Errorf("Load() = %v, want %v", got, errSecond)
+	}
+
+	if atom.CompareAndSwap(errFirst, errSecond) {
+		t.Error("CompareAndSwap() with stale old value should fail")
+	}
+
+	// An unset Error is distinct from one explicitly set to nil: swapping
+	// against a nil "old" only succeeds once the Error has actually been
+	// set to nil.
+	unset := NewError(nil)
+	if unset.CompareAndSwap(nil, errFirst) {
+		t.Error("CompareAndSwap(nil, ...) on an unset Error should fail")
+	}
+
+	set := NewError(errFirst)
+	set.Store(nil)
+	if !set.CompareAndSwap(nil, errFirst) {
+		t.Error("CompareAndSwap(nil, ...) on an Error explicitly set to nil should succeed")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"go.uber.org/atomic/internal/nocmp"
+)
+
+//go:generate go run ./internal/gen-atomicfloat -name Float64 -wrapped float64 -bits 64 -file float64.go -ext-file float64_ext.go
+
+// Float64 is an atomic wrapper around float64.
+type Float64 struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v uint64
+}
+
+// NewFloat64 creates a new Float64.
+func NewFloat64(f float64) *Float64 {
+	return &Float64{v: math.Float64bits(f)}
+}
+
+// Load atomically loads the wrapped float64.
+func (f *Float64) Load() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&f.v))
+}
+
+// Store atomically stores the passed float64.
+func (f *Float64) Store(s float64) {
+	atomic.StoreUint64(&f.v, math.Float64bits(s))
+}
+
+// CAS is an atomic compare-and-swap.
+func (f *Float64) CAS(old, new float64) bool {
+	return atomic.CompareAndSwapUint64(&f.v, math.Float64bits(old), math.Float64bits(new))
+}
+
+// MarshalJSON encodes the wrapped float64 into JSON.
+func (f *Float64) MarshalJSON() ([]byte, error) {
+	v := f.Load()
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("json: unsupported value: %v", v)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes JSON into the wrapped float64.
+func (f *Float64) UnmarshalJSON(b []byte) error {
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	f.Store(v)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestFloat64(t *testing.T) {
+	atom := NewFloat64(4.2)
+
+	t.Run("Load", func(t *testing.T) {
+		if got := atom.Load(); got != 4.2 {
+			t.Errorf("Load() = %v, want %v", got, 4.2)
+		}
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		atom.Store(1.5)
+		if got := atom.Load(); got != 1.5 {
+			t.Errorf("Load() after Store() = %v, want %v", got, 1.5)
+		}
+	})
+
+	t.Run("CAS", func(t *testing.T) {
+		if !atom.CAS(1.5, 2.5) {
+			t.Error("CAS() with correct old value should succeed")
+		}
+		if atom.CAS(1.5, 3.5) {
+			t.Error("CAS() with stale old value should fail")
+		}
+		if got := atom.Load(); got != 2.5 {
+			t.Errorf("Load() = %v, want %v", got, 2.5)
+		}
+	})
+}
+
+func TestFloat64_ConcurrentAdd(t *testing.T) {
+	atom := NewFloat64(0)
+
+	const (
+		goroutines = 50
+		perRoutine = 100
+		delta      = 0.5
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perRoutine; j++ {
+				atom.Add(delta)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := float64(goroutines*perRoutine) * delta
+	if got := atom.Load(); got != want {
+		t.Errorf("Load() after concurrent Add() = %v, want %v", got, want)
+	}
+}
+
+func TestFloat64_Sub(t *testing.T) {
+	atom := NewFloat64(10)
+	if got := atom.Sub(4); got != 6 {
+		t.Errorf("Sub() = %v, want %v", got, 6)
+	}
+}
+
+func TestFloat64_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		desc    string
+		value   float64
+		wantErr bool
+	}{
+		{desc: "normal value", value: 3.14},
+		{desc: "NaN", value: math.NaN(), wantErr: true},
+		{desc: "+Inf", value: math.Inf(1), wantErr: true},
+		{desc: "-Inf", value: math.Inf(-1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := NewFloat64(tt.value).MarshalJSON()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFloat64_JSON_RoundTrip(t *testing.T) {
+	atom := NewFloat64(1.5)
+	bs, err := json.Marshal(atom)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+
+	var got Float64
+	if err := json.Unmarshal(bs, &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got.Load() != atom.Load() {
+		t.Errorf("round-tripped value = %v, want %v", got.Load(), atom.Load())
+	}
+}
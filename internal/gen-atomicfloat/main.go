@@ -0,0 +1,254 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// gen-atomicfloat generates an atomic wrapper around a floating-point type,
+// stored as bits in a sync/atomic-backed unsigned integer. Since hardware
+// has no atomic float instructions, Add is implemented as a
+// load-compute-CAS retry loop.
+//
+//  gen-atomicfloat -name Float64 -wrapped float64 -bits 64 -file out.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	log.SetFlags(0)
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatalf("%+v", err)
+	}
+}
+
+func run(args []string) error {
+	var opts struct {
+		Name    string
+		Wrapped string
+		Bits    int
+		File    string
+		ExtFile string
+	}
+
+	flag := flag.NewFlagSet("gen-atomicfloat", flag.ContinueOnError)
+
+	flag.StringVar(&opts.Name, "name", "", "name of the generated type (e.g. Float64)")
+	flag.StringVar(&opts.Wrapped, "wrapped", "", "name of the wrapped type (e.g. float64)")
+	flag.IntVar(&opts.Bits, "bits", 64, "bit width of the underlying unsigned integer (e.g. 64)")
+	flag.StringVar(&opts.File, "file", "", "output file path (default: stdout)")
+	flag.StringVar(&opts.ExtFile, "ext-file", "", "path to the hand-maintained companion file (created once, left alone afterwards)")
+
+	if err := flag.Parse(args); err != nil {
+		return err
+	}
+
+	if len(opts.Name) == 0 || len(opts.Wrapped) == 0 {
+		return errors.New("flags -name and -wrapped are required")
+	}
+
+	data := struct {
+		Name        string
+		Wrapped     string
+		Bits        int
+		GenerateCmd string
+	}{
+		Name:        opts.Name,
+		Wrapped:     opts.Wrapped,
+		Bits:        opts.Bits,
+		GenerateCmd: "go run ./internal/gen-atomicfloat " + strings.Join(args, " "),
+	}
+
+	if err := writeTemplate(opts.File, _tmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.File, err)
+	}
+
+	if len(opts.ExtFile) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(opts.ExtFile); err == nil {
+		// Companion file already exists; it's hand-maintained, so leave it alone.
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("stat %q: %v", opts.ExtFile, err)
+	}
+
+	if err := writeTemplate(opts.ExtFile, _extTmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.ExtFile, err)
+	}
+
+	return nil
+}
+
+func writeTemplate(file string, tmpl *template.Template, data interface{}) error {
+	var w io.Writer = os.Stdout
+	if len(file) > 0 {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("create %q: %v", file, err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, data); err != nil {
+		return fmt.Errorf("render template: %v", err)
+	}
+
+	bs, err := format.Source(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("reformat source: %v", err)
+	}
+
+	_, err = w.Write(bs)
+	return err
+}
+
+var _tmpl = template.Must(template.New("float.go").Parse(`// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"go.uber.org/atomic/internal/nocmp"
+)
+
+{{ "//" }}go:generate {{ .GenerateCmd }}
+
+// {{ .Name }} is an atomic wrapper around {{ .Wrapped }}.
+type {{ .Name }} struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v uint{{ .Bits }}
+}
+
+// New{{ .Name }} creates a new {{ .Name }}.
+func New{{ .Name }}(f {{ .Wrapped }}) *{{ .Name }} {
+	return &{{ .Name }}{v: math.{{ .Name }}bits(f)}
+}
+
+// Load atomically loads the wrapped {{ .Wrapped }}.
+func (f *{{ .Name }}) Load() {{ .Wrapped }} {
+	return math.{{ .Name }}frombits(atomic.LoadUint{{ .Bits }}(&f.v))
+}
+
+// Store atomically stores the passed {{ .Wrapped }}.
+func (f *{{ .Name }}) Store(s {{ .Wrapped }}) {
+	atomic.StoreUint{{ .Bits }}(&f.v, math.{{ .Name }}bits(s))
+}
+
+// CAS is an atomic compare-and-swap.
+func (f *{{ .Name }}) CAS(old, new {{ .Wrapped }}) bool {
+	return atomic.CompareAndSwapUint{{ .Bits }}(&f.v, math.{{ .Name }}bits(old), math.{{ .Name }}bits(new))
+}
+
+// MarshalJSON encodes the wrapped {{ .Wrapped }} into JSON.
+func (f *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	v := f.Load()
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("json: unsupported value: %v", v)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes JSON into the wrapped {{ .Wrapped }}.
+func (f *{{ .Name }}) UnmarshalJSON(b []byte) error {
+	var v {{ .Wrapped }}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	f.Store(v)
+	return nil
+}
+`))
+
+// _extTmpl seeds the companion "_ext.go" file the first time it's
+// generated. Add/Sub are hand-written here since they're retry loops built
+// on the generated CAS, not a single sync/atomic call.
+var _extTmpl = template.Must(template.New("float_ext.go").Parse(`// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+// Add atomically adds to the wrapped {{ .Wrapped }} and returns the new value.
+func (f *{{ .Name }}) Add(s {{ .Wrapped }}) {{ .Wrapped }} {
+	for {
+		old := f.Load()
+		new := old + s
+		if f.CAS(old, new) {
+			return new
+		}
+	}
+}
+
+// Sub atomically subtracts from the wrapped {{ .Wrapped }} and returns the new value.
+func (f *{{ .Name }}) Sub(s {{ .Wrapped }}) {{ .Wrapped }} {
+	return f.Add(-s)
+}
+`))
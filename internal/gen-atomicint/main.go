@@ -24,6 +24,17 @@
 //
 // The generated wrapper will use the functions in the sync/atomic package
 // named after the generated type.
+//
+// Passing -ext-file additionally writes a companion file that is created
+// once and then left untouched on subsequent runs, so hand-written helpers
+// that don't belong in the generated wrapper (e.g. Bool.Toggle) have a
+// stable home that survives regeneration.
+//
+// -wrapped may differ from the underlying sync/atomic primitive: pass
+// -store-as and -atomic-suffix to wrap a named type (e.g. time.Duration)
+// around an int64, and -parse to additionally marshal through a textual
+// encoding such as time.ParseDuration instead of json.Marshal of the raw
+// number.
 package main
 
 import (
@@ -35,6 +46,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 	"text/template"
 )
 
@@ -47,10 +59,14 @@ func main() {
 
 func run(args []string) error {
 	var opts struct {
-		Name     string
-		Wrapped  string
-		File     string
-		Unsigned bool
+		Name         string
+		Wrapped      string
+		File         string
+		ExtFile      string
+		Unsigned     bool
+		StoreAs      string
+		AtomicSuffix string
+		Parse        string
 	}
 
 	flag := flag.NewFlagSet("gen-atomicint", flag.ContinueOnError)
@@ -58,7 +74,11 @@ func run(args []string) error {
 	flag.StringVar(&opts.Name, "name", "", "name of the generated type (e.g. Int32)")
 	flag.StringVar(&opts.Wrapped, "wrapped", "", "name of the wrapped type (e.g. int32)")
 	flag.StringVar(&opts.File, "file", "", "output file path (default: stdout)")
+	flag.StringVar(&opts.ExtFile, "ext-file", "", "path to the hand-maintained companion file (created once, left alone afterwards)")
 	flag.BoolVar(&opts.Unsigned, "unsigned", false, "whether the type is unsigned")
+	flag.StringVar(&opts.StoreAs, "store-as", "", "underlying sync/atomic primitive backing -wrapped (default: -wrapped itself)")
+	flag.StringVar(&opts.AtomicSuffix, "atomic-suffix", "", "sync/atomic function suffix, e.g. Int64 (default: -name)")
+	flag.StringVar(&opts.Parse, "parse", "", "a func(string) (T, error), e.g. time.ParseDuration, used to marshal through text instead of the raw number")
 
 	if err := flag.Parse(args); err != nil {
 		return err
@@ -68,8 +88,61 @@ func run(args []string) error {
 		return errors.New("flags -name and -wrapped are required")
 	}
 
+	storeAs := opts.StoreAs
+	if len(storeAs) == 0 {
+		storeAs = opts.Wrapped
+	}
+
+	atomicSuffix := opts.AtomicSuffix
+	if len(atomicSuffix) == 0 {
+		atomicSuffix = opts.Name
+	}
+
+	data := struct {
+		Name         string
+		Wrapped      string
+		Unsigned     bool
+		StoreAs      string
+		AtomicSuffix string
+		Parse        string
+		NeedsTime    bool
+		GenerateCmd  string
+	}{
+		Name:         opts.Name,
+		Wrapped:      opts.Wrapped,
+		Unsigned:     opts.Unsigned,
+		StoreAs:      storeAs,
+		AtomicSuffix: atomicSuffix,
+		Parse:        opts.Parse,
+		NeedsTime:    strings.HasPrefix(opts.Wrapped, "time."),
+		GenerateCmd:  "go run ./internal/gen-atomicint " + strings.Join(args, " "),
+	}
+
+	if err := writeTemplate(opts.File, _tmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.File, err)
+	}
+
+	if len(opts.ExtFile) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(opts.ExtFile); err == nil {
+		// Companion file already exists; it's hand-maintained, so leave it alone.
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("stat %q: %v", opts.ExtFile, err)
+	}
+
+	if err := writeTemplate(opts.ExtFile, _extTmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.ExtFile, err)
+	}
+
+	return nil
+}
+
+func writeTemplate(file string, tmpl *template.Template, data interface{}) error {
 	var w io.Writer = os.Stdout
-	if file := opts.File; len(file) > 0 {
+	if len(file) > 0 {
 		f, err := os.Create(file)
 		if err != nil {
 			return fmt.Errorf("create %q: %v", file, err)
@@ -79,18 +152,8 @@ func run(args []string) error {
 		w = f
 	}
 
-	data := struct {
-		Name     string
-		Wrapped  string
-		Unsigned bool
-	}{
-		Name:     opts.Name,
-		Wrapped:  opts.Wrapped,
-		Unsigned: opts.Unsigned,
-	}
-
 	var buff bytes.Buffer
-	if err := _tmpl.Execute(&buff, data); err != nil {
+	if err := tmpl.Execute(&buff, data); err != nil {
 		return fmt.Errorf("render template: %v", err)
 	}
 
@@ -128,35 +191,46 @@ package atomic
 import (
 	"encoding/json"
 	"sync/atomic"
+	{{- if .NeedsTime }}
+	"time"
+	{{- end }}
+
+	"go.uber.org/atomic/internal/nocmp"
 )
 
+{{ "//" }}go:generate {{ .GenerateCmd }}
+
 // {{ .Name }} is an atomic wrapper around {{ .Wrapped }}.
-type {{ .Name }} struct{ v {{ .Wrapped }} }
+type {{ .Name }} struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v {{ .StoreAs }}
+}
 
 // New{{ .Name }} creates a new {{ .Name }}.
 func New{{ .Name }}(i {{ .Wrapped }}) *{{ .Name }} {
-	return &{{ .Name }}{i}
+	return &{{ .Name }}{v: {{ .StoreAs }}(i)}
 }
 
 // Load atomically loads the wrapped value.
 func (i *{{ .Name }}) Load() {{ .Wrapped }} {
-	return atomic.Load{{ .Name }}(&i.v)
+	return {{ .Wrapped }}(atomic.Load{{ .AtomicSuffix }}(&i.v))
 }
 
 // Add atomically adds to the wrapped {{ .Wrapped }} and returns the new value.
 func (i *{{ .Name }}) Add(n {{ .Wrapped }}) {{ .Wrapped }} {
-	return atomic.Add{{ .Name }}(&i.v, n)
+	return {{ .Wrapped }}(atomic.Add{{ .AtomicSuffix }}(&i.v, {{ .StoreAs }}(n)))
 }
 
 // Sub atomically subtracts from the wrapped {{ .Wrapped }} and returns the new value.
 func (i *{{ .Name }}) Sub(n {{ .Wrapped }}) {{ .Wrapped }} {
-	return atomic.Add{{ .Name }}(&i.v,
+	return {{ .Wrapped }}(atomic.Add{{ .AtomicSuffix }}(&i.v,
 		{{- if .Unsigned -}}
-			^(n - 1)
+			^({{ .StoreAs }}(n) - 1)
 		{{- else -}}
-			-n
+			-{{ .StoreAs }}(n)
 		{{- end -}}
-	)
+	))
 }
 
 // Inc atomically increments the wrapped {{ .Wrapped }} and returns the new value.
@@ -171,31 +245,83 @@ func (i *{{ .Name }}) Dec() {{ .Wrapped }} {
 
 // CAS is an atomic compare-and-swap.
 func (i *{{ .Name }}) CAS(old, new {{ .Wrapped }}) bool {
-	return atomic.CompareAndSwap{{ .Name }}(&i.v, old, new)
+	return atomic.CompareAndSwap{{ .AtomicSuffix }}(&i.v, {{ .StoreAs }}(old), {{ .StoreAs }}(new))
 }
 
 // Store atomically stores the passed value.
 func (i *{{ .Name }}) Store(n {{ .Wrapped }}) {
-	atomic.Store{{ .Name }}(&i.v, n)
+	atomic.Store{{ .AtomicSuffix }}(&i.v, {{ .StoreAs }}(n))
 }
 
 // Swap atomically swaps the wrapped {{ .Wrapped }} and returns the old value.
 func (i *{{ .Name }}) Swap(n {{ .Wrapped }}) {{ .Wrapped }} {
-	return atomic.Swap{{ .Name }}(&i.v, n)
+	return {{ .Wrapped }}(atomic.Swap{{ .AtomicSuffix }}(&i.v, {{ .StoreAs }}(n)))
 }
-
+{{ if .Parse }}
+// String encodes the wrapped value as a string.
+func (i *{{ .Name }}) String() string {
+	return i.Load().String()
+}
+{{ end }}
 // MarshalJSON encodes the wrapped {{ .Wrapped }} into JSON.
 func (i *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	{{ if .Parse -}}
+	return json.Marshal(i.Load().String())
+	{{- else -}}
 	return json.Marshal(i.Load())
+	{{- end }}
 }
 
 // UnmarshalJSON decodes JSON into the wrapped {{ .Wrapped }}.
 func (i *{{ .Name }}) UnmarshalJSON(b []byte) error {
+	{{ if .Parse -}}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := {{ .Parse }}(s)
+	if err != nil {
+		return err
+	}
+	i.Store(v)
+	return nil
+	{{- else -}}
 	var v {{ .Wrapped }}
 	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
 	i.Store(v)
 	return nil
+	{{- end }}
 }
 `))
+
+// _extTmpl seeds the companion "_ext.go" file the first time it's
+// generated. It is intentionally minimal: everything beyond this point is
+// hand-maintained and gen-atomicint will not touch the file again.
+var _extTmpl = template.Must(template.New("int_ext.go").Parse(`// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+// This file is generated once by gen-atomicint and then left alone: it is
+// the home for {{ .Name }} helpers that don't fit the mechanical
+// Load/Store/CAS/Add/Sub/Swap/JSON surface in {{ .Name }}'s generated file.
+`))
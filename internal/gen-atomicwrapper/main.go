@@ -0,0 +1,263 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// gen-atomicwrapper generates an atomic, type-safe wrapper around an
+// interface-typed value, backed by sync/atomic.Value. Unlike gen-atomicint,
+// the wrapped type isn't itself comparable enough for atomic.Value to store
+// directly (or needs to distinguish "unset" from "explicitly set to the
+// zero value"), so callers provide a small packed carrier type plus
+// pack/unpack functions between -type and that carrier:
+//
+//  gen-atomicwrapper -name Error -type error -wrapped storedError \
+//  	-pack wrapError -unpack unwrapError -file out.go
+//
+// The pack/unpack functions and the carrier type are hand-written; see
+// error_ext.go for an example.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	log.SetFlags(0)
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatalf("%+v", err)
+	}
+}
+
+func run(args []string) error {
+	var opts struct {
+		Name    string
+		Type    string
+		Wrapped string
+		Pack    string
+		Unpack  string
+		File    string
+		ExtFile string
+	}
+
+	flag := flag.NewFlagSet("gen-atomicwrapper", flag.ContinueOnError)
+
+	flag.StringVar(&opts.Name, "name", "", "name of the generated type (e.g. Error)")
+	flag.StringVar(&opts.Type, "type", "", "name of the wrapped interface type (e.g. error)")
+	flag.StringVar(&opts.Wrapped, "wrapped", "", "name of the packed carrier type stored in Value (e.g. storedError)")
+	flag.StringVar(&opts.Pack, "pack", "", "func(-type) -wrapped that packs a value for storage")
+	flag.StringVar(&opts.Unpack, "unpack", "", "func(-wrapped) -type that unpacks a stored value")
+	flag.StringVar(&opts.File, "file", "", "output file path (default: stdout)")
+	flag.StringVar(&opts.ExtFile, "ext-file", "", "path to the hand-maintained companion file (created once, left alone afterwards)")
+
+	if err := flag.Parse(args); err != nil {
+		return err
+	}
+
+	if len(opts.Name) == 0 || len(opts.Type) == 0 || len(opts.Wrapped) == 0 || len(opts.Pack) == 0 || len(opts.Unpack) == 0 {
+		return errors.New("flags -name, -type, -wrapped, -pack, and -unpack are required")
+	}
+
+	data := struct {
+		Name        string
+		Type        string
+		Wrapped     string
+		Pack        string
+		Unpack      string
+		GenerateCmd string
+	}{
+		Name:        opts.Name,
+		Type:        opts.Type,
+		Wrapped:     opts.Wrapped,
+		Pack:        opts.Pack,
+		Unpack:      opts.Unpack,
+		GenerateCmd: "go run ./internal/gen-atomicwrapper " + strings.Join(args, " "),
+	}
+
+	if err := writeTemplate(opts.File, _tmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.File, err)
+	}
+
+	if len(opts.ExtFile) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(opts.ExtFile); err == nil {
+		// Companion file already exists; it's hand-maintained, so leave it alone.
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("stat %q: %v", opts.ExtFile, err)
+	}
+
+	if err := writeTemplate(opts.ExtFile, _extTmpl, data); err != nil {
+		return fmt.Errorf("write %q: %v", opts.ExtFile, err)
+	}
+
+	return nil
+}
+
+func writeTemplate(file string, tmpl *template.Template, data interface{}) error {
+	var w io.Writer = os.Stdout
+	if len(file) > 0 {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("create %q: %v", file, err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, data); err != nil {
+		return fmt.Errorf("render template: %v", err)
+	}
+
+	bs, err := format.Source(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("reformat source: %v", err)
+	}
+
+	_, err = w.Write(bs)
+	return err
+}
+
+var _tmpl = template.Must(template.New("wrapper.go").Parse(`// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/atomic/internal/nocmp"
+)
+
+{{ "//" }}go:generate {{ .GenerateCmd }}
+
+// {{ .Name }} is an atomic type-safe wrapper for {{ .Type }} values.
+type {{ .Name }} struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v atomic.Value
+}
+
+// New{{ .Name }} creates a new {{ .Name }}.
+func New{{ .Name }}(v {{ .Type }}) *{{ .Name }} {
+	x := &{{ .Name }}{}
+	if v != nil {
+		x.Store(v)
+	}
+	return x
+}
+
+// Load atomically loads the wrapped {{ .Type }}.
+func (x *{{ .Name }}) Load() {{ .Type }} {
+	v := x.v.Load()
+	if v == nil {
+		return nil
+	}
+	return {{ .Unpack }}(v.({{ .Wrapped }}))
+}
+
+// Store atomically stores the passed {{ .Type }}.
+//
+// NOTE: This will cause an allocation.
+func (x *{{ .Name }}) Store(v {{ .Type }}) {
+	x.v.Store({{ .Pack }}(v))
+}
+
+// CompareAndSwap is an atomic compare-and-swap for {{ .Type }} values.
+func (x *{{ .Name }}) CompareAndSwap(old, new {{ .Type }}) (swapped bool) {
+	return x.v.CompareAndSwap({{ .Pack }}(old), {{ .Pack }}(new))
+}
+
+// Swap atomically stores the given {{ .Type }} and returns the old
+// {{ .Type }} that was previously wrapped, if any existed.
+func (x *{{ .Name }}) Swap(new {{ .Type }}) (old {{ .Type }}) {
+	v := x.v.Swap({{ .Pack }}(new))
+	if v == nil {
+		return nil
+	}
+	return {{ .Unpack }}(v.({{ .Wrapped }}))
+}
+`))
+
+// _extTmpl seeds the companion "_ext.go" file the first time it's
+// generated. This is where the packed carrier type and the pack/unpack
+// functions the wrapper's generated code calls into actually live.
+var _extTmpl = template.Must(template.New("wrapper_ext.go").Parse(`// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package atomic
+
+// {{ .Wrapped }} carries a {{ .Type }} through Value, which cannot store a
+// nil interface directly and needs a comparable value to distinguish
+// "never stored" from "stored".
+type {{ .Wrapped }} struct{ Value {{ .Type }} }
+
+func {{ .Pack }}(v {{ .Type }}) {{ .Wrapped }} {
+	return {{ .Wrapped }}{v}
+}
+
+func {{ .Unpack }}(v {{ .Wrapped }}) {{ .Type }} {
+	return v.Value
+}
+`))
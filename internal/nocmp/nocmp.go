@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package nocmp provides a helper type to avoid comparability for structs
+// and improve the performance when the Go compiler has to generate an
+// equality function for them.
+package nocmp
+
+// NoCompare is an uncomparable struct. Add it as a field to another struct
+// to make that struct uncomparable.
+//
+//	type Foo struct {
+//		nocmp.NoCompare
+//		// ...
+//	}
+//
+// This discourages accidental use of "==" on the containing type, which for
+// atomic wrappers would silently compare internal state rather than the
+// values they guard. NoCompare must be exported so that other packages can
+// refer to it; it has no exported fields or methods of its own.
+type NoCompare [0]func()
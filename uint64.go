@@ -23,29 +23,37 @@ package atomic
 import (
 	"encoding/json"
 	"sync/atomic"
+
+	"go.uber.org/atomic/internal/nocmp"
 )
 
+//go:generate go run ./internal/gen-atomicint -name Uint64 -wrapped uint64 -file uint64.go -ext-file uint64_ext.go -unsigned
+
 // Uint64 is an atomic wrapper around uint64.
-type Uint64 struct{ v uint64 }
+type Uint64 struct {
+	_ nocmp.NoCompare // disallow non-atomic comparison
+
+	v uint64
+}
 
 // NewUint64 creates a new Uint64.
 func NewUint64(i uint64) *Uint64 {
-	return &Uint64{i}
+	return &Uint64{v: uint64(i)}
 }
 
 // Load atomically loads the wrapped value.
 func (i *Uint64) Load() uint64 {
-	return atomic.LoadUint64(&i.v)
+	return uint64(atomic.LoadUint64(&i.v))
 }
 
 // Add atomically adds to the wrapped uint64 and returns the new value.
 func (i *Uint64) Add(n uint64) uint64 {
-	return atomic.AddUint64(&i.v, n)
+	return uint64(atomic.AddUint64(&i.v, uint64(n)))
 }
 
 // Sub atomically subtracts from the wrapped uint64 and returns the new value.
 func (i *Uint64) Sub(n uint64) uint64 {
-	return atomic.AddUint64(&i.v, ^(n - 1))
+	return uint64(atomic.AddUint64(&i.v, ^(uint64(n) - 1)))
 }
 
 // Inc atomically increments the wrapped uint64 and returns the new value.
@@ -60,17 +68,17 @@ func (i *Uint64) Dec() uint64 {
 
 // CAS is an atomic compare-and-swap.
 func (i *Uint64) CAS(old, new uint64) bool {
-	return atomic.CompareAndSwapUint64(&i.v, old, new)
+	return atomic.CompareAndSwapUint64(&i.v, uint64(old), uint64(new))
 }
 
 // Store atomically stores the passed value.
 func (i *Uint64) Store(n uint64) {
-	atomic.StoreUint64(&i.v, n)
+	atomic.StoreUint64(&i.v, uint64(n))
 }
 
 // Swap atomically swaps the wrapped uint64 and returns the old value.
 func (i *Uint64) Swap(n uint64) uint64 {
-	return atomic.SwapUint64(&i.v, n)
+	return uint64(atomic.SwapUint64(&i.v, uint64(n)))
 }
 
 // MarshalJSON encodes the wrapped uint64 into JSON.